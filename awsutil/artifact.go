@@ -0,0 +1,113 @@
+package awsutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//EbsVolID identifies an EBS volume by its vol-* id
+type EbsVolID string
+
+//Artifact records every volume goat created (as opposed to discovered) so a
+//failed or partial bootstrap can be rolled back with Destroy instead of
+//leaking volumes
+type Artifact struct {
+	Region  string                `json:"region"`
+	Volumes map[string][]EbsVolID `json:"volumes"`
+}
+
+//NewArtifact returns an empty Artifact scoped to region
+func NewArtifact(region string) *Artifact {
+	return &Artifact{Region: region, Volumes: map[string][]EbsVolID{}}
+}
+
+//Add records that volID was created for volName
+func (a *Artifact) Add(volName string, volID EbsVolID) {
+	a.Volumes[volName] = append(a.Volumes[volName], volID)
+}
+
+//Save serializes the artifact to path as JSON
+func (a *Artifact) Save(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0o600)
+}
+
+//LoadArtifact reads back an Artifact previously written by Save
+func LoadArtifact(path string) (*Artifact, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := &Artifact{}
+	if err := json.Unmarshal(data, artifact); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+//Destroy detaches and deletes every volume recorded in the artifact. It
+//keeps going even after a volume fails so one transient AWS error can't
+//abandon the rest of the rollback, and returns every error it hit joined
+//together (nil if all volumes were destroyed)
+func (a *Artifact) Destroy(ctx context.Context, client EC2API) error {
+	var errs []error
+	for volName, ids := range a.Volumes {
+		for _, id := range ids {
+			if err := destroyVolume(ctx, client, string(id)); err != nil {
+				errs = append(errs, fmt.Errorf("destroying volume %s (%s): %v", id, volName, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func destroyVolume(ctx context.Context, client EC2API, volID string) error {
+	volLogger := log.WithFields(log.Fields{"vol_id": volID})
+
+	_, err := client.DetachVolume(&ec2.DetachVolumeInput{
+		VolumeId: aws.String(volID),
+		Force:    aws.Bool(true),
+	})
+	switch {
+	case isAWSErrCode(err, "IncorrectState"):
+		volLogger.Info("Volume already detached")
+	case isAWSErrCode(err, "InvalidVolume.NotFound"):
+		volLogger.Info("Volume already deleted")
+		return nil
+	case err != nil:
+		return fmt.Errorf("detaching: %v", err)
+	default:
+		if err := waitForVolumeState(ctx, client, volID, ec2.VolumeStateAvailable); err != nil {
+			return fmt.Errorf("waiting for detach: %v", err)
+		}
+	}
+
+	if _, err := client.DeleteVolume(&ec2.DeleteVolumeInput{VolumeId: aws.String(volID)}); err != nil && !isAWSErrCode(err, "InvalidVolume.NotFound") {
+		return fmt.Errorf("deleting: %v", err)
+	}
+
+	if err := waitForVolumeDeleted(ctx, client, volID); err != nil {
+		return fmt.Errorf("waiting for delete: %v", err)
+	}
+
+	volLogger.Info("Volume destroyed")
+	return nil
+}
+
+func isAWSErrCode(err error, code string) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == code
+}
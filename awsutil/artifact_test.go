@@ -0,0 +1,60 @@
+package awsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+
+	"github.com/alexballas/goat/awsutil/mocks"
+)
+
+func TestArtifactSaveLoad(t *testing.T) {
+	artifact := NewArtifact("eu-west-1")
+	artifact.Add("data", "vol-1")
+	artifact.Add("data", "vol-2")
+
+	path := filepath.Join(t.TempDir(), "artifact.json")
+	if err := artifact.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadArtifact(path)
+	if err != nil {
+		t.Fatalf("LoadArtifact: %v", err)
+	}
+	if loaded.Region != "eu-west-1" || len(loaded.Volumes["data"]) != 2 {
+		t.Fatalf("unexpected loaded artifact: %+v", loaded)
+	}
+}
+
+func TestLoadArtifactMissingFile(t *testing.T) {
+	if _, err := LoadArtifact(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+}
+
+func TestArtifactDestroy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	artifact := NewArtifact("eu-west-1")
+	artifact.Add("data", "vol-1")
+
+	client.EXPECT().DetachVolume(gomock.Any()).Return(&ec2.VolumeAttachment{}, nil)
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{State: aws.String(ec2.VolumeStateAvailable)}},
+	}, nil)
+	client.EXPECT().DeleteVolume(gomock.Any()).Return(&ec2.DeleteVolumeOutput{}, nil)
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(nil, awserr.New("InvalidVolume.NotFound", "gone", nil))
+
+	if err := artifact.Destroy(context.Background(), client); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+}
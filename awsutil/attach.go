@@ -0,0 +1,174 @@
+package awsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//ErrVolumeAttachedElsewhere is returned by AttachVolumes when AWS reports a
+//volume as VolumeInUse on an instance other than this one
+var ErrVolumeAttachedElsewhere = errors.New("volume is attached to a different instance")
+
+const (
+	attachPollInterval    = 2 * time.Second
+	attachDefaultDeadline = 5 * time.Minute
+)
+
+type attachmentState int
+
+const (
+	attachmentUnknown attachmentState = iota
+	attachedToThisInstance
+	attachedElsewhere
+)
+
+func devicePool() []string {
+	names := make([]string, 0, 11)
+	for c := 'f'; c <= 'p'; c++ {
+		names = append(names, fmt.Sprintf("/dev/xvd%c", c))
+	}
+	return names
+}
+
+//AttachVolumes attaches every discovered-but-unattached volume in e.Vols to
+//this instance, allocating device names from devicePool while skipping any
+//device already reported by DescribeInstances. deadline bounds how long
+//goat will retry a single volume before giving up; a zero value falls back
+//to attachDefaultDeadline
+func (e *EC2Instance) AttachVolumes(deadline time.Duration) error {
+	if deadline <= 0 {
+		deadline = attachDefaultDeadline
+	}
+
+	used, err := e.usedDeviceNames()
+	if err != nil {
+		return fmt.Errorf("listing existing block device mappings: %v", err)
+	}
+
+	for volName, vols := range e.Vols {
+		for i := range vols {
+			if vols[i].AttachedName != "" {
+				continue
+			}
+
+			device, err := nextFreeDevice(used)
+			if err != nil {
+				return fmt.Errorf("attaching %s: %v", volName, err)
+			}
+
+			if err := e.attachVolume(&vols[i], device, deadline); err != nil {
+				return fmt.Errorf("attaching volume %s (%s): %w", vols[i].EbsVolID, volName, err)
+			}
+			used[vols[i].AttachedName] = true
+		}
+	}
+
+	return nil
+}
+
+func nextFreeDevice(used map[string]bool) (string, error) {
+	for _, d := range devicePool() {
+		if !used[d] {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("no free device names left in the /dev/xvdf-/dev/xvdp pool")
+}
+
+func (e *EC2Instance) usedDeviceNames() (map[string]bool, error) {
+	result, err := e.EC2Client.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(e.InstanceID)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			for _, mapping := range instance.BlockDeviceMappings {
+				used[*mapping.DeviceName] = true
+			}
+		}
+	}
+	return used, nil
+}
+
+func (e *EC2Instance) attachVolume(vol *EbsVol, device string, deadline time.Duration) error {
+	volLogger := log.WithFields(log.Fields{"vol_id": vol.EbsVolID, "device": device})
+	deadlineAt := time.Now().Add(deadline)
+	backoff := attachPollInterval
+
+	for {
+		_, err := e.EC2Client.AttachVolume(&ec2.AttachVolumeInput{
+			VolumeId:   aws.String(vol.EbsVolID),
+			InstanceId: aws.String(e.InstanceID),
+			Device:     aws.String(device),
+		})
+
+		if err != nil {
+			aerr, ok := err.(awserr.Error)
+			if !ok || aerr.Code() != "VolumeInUse" {
+				return err
+			}
+
+			state, observedDevice, oErr := e.attachmentOwner(vol.EbsVolID)
+			if oErr != nil {
+				return oErr
+			}
+
+			switch state {
+			case attachedToThisInstance:
+				volLogger.Info("Volume already attached to this instance")
+				device = observedDevice
+			case attachedElsewhere:
+				return ErrVolumeAttachedElsewhere
+			default:
+				if time.Now().After(deadlineAt) {
+					return fmt.Errorf("volume %s still reports VolumeInUse after deadline", vol.EbsVolID)
+				}
+				volLogger.Warn("Volume reported VolumeInUse, retrying")
+				time.Sleep(backoff)
+				backoff *= 2
+				continue
+			}
+		}
+
+		break
+	}
+
+	if err := waitForAttached(context.Background(), e.EC2Client, vol.EbsVolID); err != nil {
+		return err
+	}
+
+	vol.AttachedName = device
+	return nil
+}
+
+func (e *EC2Instance) attachmentOwner(volID string) (attachmentState, string, error) {
+	result, err := e.EC2Client.DescribeVolumes(&ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volID)},
+	})
+	if err != nil {
+		return attachmentUnknown, "", err
+	}
+	if len(result.Volumes) != 1 {
+		return attachmentUnknown, "", fmt.Errorf("expected 1 volume for %s, got %d", volID, len(result.Volumes))
+	}
+
+	for _, attachment := range result.Volumes[0].Attachments {
+		if *attachment.InstanceId == e.InstanceID {
+			return attachedToThisInstance, *attachment.Device, nil
+		}
+		return attachedElsewhere, "", nil
+	}
+	return attachmentUnknown, "", nil
+}
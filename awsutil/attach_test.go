@@ -0,0 +1,162 @@
+package awsutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+
+	"github.com/alexballas/goat/awsutil/mocks"
+)
+
+func TestAttachVolumesSkipsAlreadyAttached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{
+		EC2Client:  client,
+		InstanceID: "i-123",
+		Vols: map[string][]EbsVol{
+			"data": {{EbsVolID: "vol-1", AttachedName: "/dev/xvdf"}},
+		},
+	}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{BlockDeviceMappings: nil}}}},
+	}, nil)
+
+	if err := e.AttachVolumes(time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAttachVolumesAttachesFreeDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{
+		EC2Client:  client,
+		InstanceID: "i-123",
+		Vols: map[string][]EbsVol{
+			"data": {{EbsVolID: "vol-1"}},
+		},
+	}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{
+			BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{{DeviceName: aws.String("/dev/xvdf")}},
+		}}}},
+	}, nil)
+	client.EXPECT().AttachVolume(gomock.Any()).DoAndReturn(func(in *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+		if *in.Device != "/dev/xvdg" {
+			t.Fatalf("expected /dev/xvdg to be allocated, got %s", *in.Device)
+		}
+		return &ec2.VolumeAttachment{}, nil
+	})
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{
+			State:       aws.String(ec2.VolumeStateInUse),
+			Attachments: []*ec2.VolumeAttachment{{State: aws.String(ec2.VolumeAttachmentStateAttached)}},
+		}},
+	}, nil)
+
+	if err := e.AttachVolumes(time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Vols["data"][0].AttachedName != "/dev/xvdg" {
+		t.Fatalf("expected AttachedName to be set, got %q", e.Vols["data"][0].AttachedName)
+	}
+}
+
+func TestAttachVolumesMarksObservedDeviceUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{
+		EC2Client:  client,
+		InstanceID: "i-123",
+		Vols: map[string][]EbsVol{
+			"data": {{EbsVolID: "vol-1"}, {EbsVolID: "vol-2"}},
+		},
+	}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{}}}},
+	}, nil)
+
+	// vol-1 asks for /dev/xvdf but AWS reports it already attached to this
+	// instance at /dev/xvdg instead.
+	client.EXPECT().AttachVolume(gomock.Any()).DoAndReturn(func(in *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+		if *in.Device != "/dev/xvdf" {
+			t.Fatalf("expected vol-1 to request /dev/xvdf, got %s", *in.Device)
+		}
+		return nil, awserr.New("VolumeInUse", "in use", nil)
+	})
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{
+			State:       aws.String(ec2.VolumeStateInUse),
+			Attachments: []*ec2.VolumeAttachment{{InstanceId: aws.String("i-123"), Device: aws.String("/dev/xvdg"), State: aws.String(ec2.VolumeAttachmentStateAttached)}},
+		}},
+	}, nil).Times(2)
+
+	// vol-2 must get /dev/xvdf (still genuinely free), not /dev/xvdg, which
+	// vol-1 actually ended up on.
+	client.EXPECT().AttachVolume(gomock.Any()).DoAndReturn(func(in *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+		if *in.Device != "/dev/xvdf" {
+			t.Fatalf("expected vol-2 to be allocated the still-free /dev/xvdf, got %s", *in.Device)
+		}
+		return &ec2.VolumeAttachment{}, nil
+	})
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{
+			State:       aws.String(ec2.VolumeStateInUse),
+			Attachments: []*ec2.VolumeAttachment{{State: aws.String(ec2.VolumeAttachmentStateAttached)}},
+		}},
+	}, nil)
+
+	if err := e.AttachVolumes(time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Vols["data"][0].AttachedName != "/dev/xvdg" {
+		t.Fatalf("expected vol-1 AttachedName to be the AWS-reported device, got %q", e.Vols["data"][0].AttachedName)
+	}
+	if e.Vols["data"][1].AttachedName != "/dev/xvdf" {
+		t.Fatalf("expected vol-2 AttachedName to be /dev/xvdf, got %q", e.Vols["data"][1].AttachedName)
+	}
+}
+
+func TestAttachVolumesElsewhereIsTypedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{
+		EC2Client:  client,
+		InstanceID: "i-123",
+		Vols: map[string][]EbsVol{
+			"data": {{EbsVolID: "vol-1"}},
+		},
+	}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{}}}},
+	}, nil)
+	client.EXPECT().AttachVolume(gomock.Any()).Return(nil, awserr.New("VolumeInUse", "in use", nil))
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{
+			Attachments: []*ec2.VolumeAttachment{{InstanceId: aws.String("i-999")}},
+		}},
+	}, nil)
+
+	err := e.AttachVolumes(time.Second)
+	if !errors.Is(err, ErrVolumeAttachedElsewhere) {
+		t.Fatalf("expected ErrVolumeAttachedElsewhere, got %v", err)
+	}
+}
@@ -18,6 +18,10 @@ type EbsVol struct {
 	AttachedName string
 	MountPath    string
 	FsType       string
+	//CreateOpts is non-nil when the volume's tags describe how to provision a
+	//sibling of this volume. ProvisionMissingVolumes uses it as a template, and
+	//FindEbsVolumes validates it and compares its VolumeType across the group
+	CreateOpts *CreateOpts
 }
 
 //FindEbsVolumes discovers and creates a {'VolumeName':[]EbsVol} map for all the required EBS volumes given an EC2Instance struct
@@ -44,13 +48,27 @@ func (e *EC2Instance) FindEbsVolumes() {
 		mountPath := volumes[0].MountPath
 		fsType := volumes[0].FsType
 		raidLevel := volumes[0].RaidLevel
+		volType := volumeType(volumes[0])
+		for _, vol := range volumes {
+			if err := validateVolumeType(vol.CreateOpts); err != nil {
+				volGroupLogger.Fatalf("Invalid GOAT-IN:VolumeType tags: %v", err)
+			}
+		}
+
 		if volSize != -1 {
-			if len(volumes) != volSize {
+			if len(volumes) < volSize {
+				created, err := e.ProvisionMissingVolumes(volName, volumes, volSize-len(volumes))
+				if err != nil {
+					volGroupLogger.Fatalf("Error provisioning missing volumes: %v", err)
+				}
+				volumes = append(volumes, created...)
+				drivesToMount[volName] = volumes
+			} else if len(volumes) > volSize {
 				volGroupLogger.Fatalf("Found %d volumes, expected %d from VolumeSize tag", len(volumes), volSize)
 			}
 			for _, vol := range volumes[1:] {
 				volLogger := log.WithFields(log.Fields{"vol_id": vol.EbsVolID, "vol_name": vol.VolumeName})
-				if volSize != vol.VolumeSize || mountPath != vol.MountPath || fsType != vol.FsType || raidLevel != vol.RaidLevel {
+				if volSize != vol.VolumeSize || mountPath != vol.MountPath || fsType != vol.FsType || raidLevel != vol.RaidLevel || volType != volumeType(vol) {
 					volLogger.Fatal("Mismatched tags among disks of same volume")
 				}
 			}
@@ -126,6 +144,30 @@ func (e *EC2Instance) findEbsVolumes() ([]EbsVol, error) {
 				ebsVolume.MountPath = *tag.Value
 			case "GOAT-IN:FsType":
 				ebsVolume.FsType = *tag.Value
+			case "GOAT-IN:SizeGiB":
+				sizeGiB, err := strconv.ParseInt(*tag.Value, 10, 64)
+				if err != nil {
+					return volumes, fmt.Errorf("Couldn't parse SizeGiB tag as int: %v", err)
+				}
+				createOpts(&ebsVolume).SizeGiB = sizeGiB
+			case "GOAT-IN:VolumeType":
+				createOpts(&ebsVolume).VolumeType = *tag.Value
+			case "GOAT-IN:Iops":
+				iops, err := strconv.ParseInt(*tag.Value, 10, 64)
+				if err != nil {
+					return volumes, fmt.Errorf("Couldn't parse Iops tag as int: %v", err)
+				}
+				createOpts(&ebsVolume).Iops = iops
+			case "GOAT-IN:Throughput":
+				throughput, err := strconv.ParseInt(*tag.Value, 10, 64)
+				if err != nil {
+					return volumes, fmt.Errorf("Couldn't parse Throughput tag as int: %v", err)
+				}
+				createOpts(&ebsVolume).Throughput = throughput
+			case "GOAT-IN:KmsKeyID":
+				createOpts(&ebsVolume).KmsKeyID = *tag.Value
+			case "GOAT-IN:OutpostArn":
+				createOpts(&ebsVolume).OutpostArn = *tag.Value
 			default:
 			}
 		}
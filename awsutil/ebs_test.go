@@ -0,0 +1,113 @@
+package awsutil
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+
+	"github.com/alexballas/goat/awsutil/mocks"
+)
+
+func tagOf(key, value string) *ec2.Tag {
+	return &ec2.Tag{Key: aws.String(key), Value: aws.String(value)}
+}
+
+func TestFindEbsVolumes(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance EC2Instance
+		volumes  []*ec2.Volume
+		wantErr  bool
+		wantVols []EbsVol
+	}{
+		{
+			name:     "single volume, no group tags",
+			instance: EC2Instance{InstanceID: "i-123", Prefix: "goat", NodeID: "node1", Az: "eu-west-1a"},
+			volumes: []*ec2.Volume{
+				{
+					VolumeId: aws.String("vol-1"),
+					Tags: []*ec2.Tag{
+						tagOf("GOAT-IN:VolumeName", "data"),
+						tagOf("GOAT-IN:RaidLevel", "0"),
+						tagOf("GOAT-IN:MountPath", "/data"),
+						tagOf("GOAT-IN:FsType", "ext4"),
+					},
+				},
+			},
+			wantVols: []EbsVol{
+				{EbsVolID: "vol-1", VolumeName: "data", RaidLevel: 0, VolumeSize: -1, MountPath: "/data", FsType: "ext4"},
+			},
+		},
+		{
+			name:     "parses CreateOpts tags",
+			instance: EC2Instance{InstanceID: "i-123", Prefix: "goat", NodeID: "node1", Az: "eu-west-1a"},
+			volumes: []*ec2.Volume{
+				{
+					VolumeId: aws.String("vol-1"),
+					Tags: []*ec2.Tag{
+						tagOf("GOAT-IN:VolumeName", "data"),
+						tagOf("GOAT-IN:SizeGiB", "100"),
+						tagOf("GOAT-IN:VolumeType", VolumeTypeGp3),
+						tagOf("GOAT-IN:Iops", "3000"),
+						tagOf("GOAT-IN:Throughput", "125"),
+						tagOf("GOAT-IN:KmsKeyID", "arn:aws:kms:key"),
+					},
+				},
+			},
+			wantVols: []EbsVol{
+				{
+					EbsVolID: "vol-1", VolumeName: "data", RaidLevel: -1, VolumeSize: -1,
+					CreateOpts: &CreateOpts{SizeGiB: 100, VolumeType: VolumeTypeGp3, Iops: 3000, Throughput: 125, KmsKeyID: "arn:aws:kms:key"},
+				},
+			},
+		},
+		{
+			name:     "attached to a different instance is an error",
+			instance: EC2Instance{InstanceID: "i-123"},
+			volumes: []*ec2.Volume{
+				{
+					VolumeId:    aws.String("vol-1"),
+					Attachments: []*ec2.VolumeAttachment{{InstanceId: aws.String("i-999"), Device: aws.String("/dev/xvdf")}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mocks.NewMockEC2API(ctrl)
+			client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: tt.volumes}, nil)
+
+			tt.instance.EC2Client = client
+			got, err := tt.instance.findEbsVolumes()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.wantVols) {
+				t.Fatalf("got %d volumes, want %d", len(got), len(tt.wantVols))
+			}
+			for i, want := range tt.wantVols {
+				if got[i].EbsVolID != want.EbsVolID || got[i].VolumeName != want.VolumeName {
+					t.Errorf("volume %d = %+v, want %+v", i, got[i], want)
+				}
+				if want.CreateOpts != nil {
+					if got[i].CreateOpts == nil || *got[i].CreateOpts != *want.CreateOpts {
+						t.Errorf("volume %d CreateOpts = %+v, want %+v", i, got[i].CreateOpts, want.CreateOpts)
+					}
+				}
+			}
+		})
+	}
+}
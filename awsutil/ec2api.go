@@ -0,0 +1,20 @@
+package awsutil
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//go:generate mockgen -source=ec2api.go -destination=mocks/mock_ec2api.go -package=mocks
+
+//EC2API is the subset of *ec2.EC2 that goat calls into. Depending on the
+//interface instead of the concrete SDK client lets tests substitute a
+//gomock-generated fake instead of hitting AWS
+type EC2API interface {
+	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
+	CreateVolume(*ec2.CreateVolumeInput) (*ec2.CreateVolumeOutput, error)
+	AttachVolume(*ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error)
+	DetachVolume(*ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
+	DeleteVolume(*ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
+	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+	CreateTags(*ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error)
+}
@@ -0,0 +1,17 @@
+package awsutil
+
+//EC2Instance describes the local EC2 instance goat is running on, along with
+//the EBS volumes it has discovered or provisioned for it
+type EC2Instance struct {
+	EC2Client  EC2API
+	InstanceID string
+	Prefix     string
+	NodeID     string
+	Az         string
+	Region     string
+	Vols       map[string][]EbsVol
+	//Artifact tracks volumes created by this run so they can be rolled back
+	//with Artifact.Destroy if a bootstrap fails partway. It is nil until the
+	//first volume is provisioned
+	Artifact *Artifact
+}
@@ -0,0 +1,77 @@
+package awsutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const (
+	volumePollInterval = 2 * time.Second
+	volumePollMaxWait  = 5 * time.Minute
+)
+
+func pollVolume(ctx context.Context, client EC2API, volID string, check func(vol *ec2.Volume) (bool, error)) error {
+	deadline := time.Now().Add(volumePollMaxWait)
+	backoff := volumePollInterval
+
+	for {
+		result, err := client.DescribeVolumes(&ec2.DescribeVolumesInput{
+			VolumeIds: []*string{aws.String(volID)},
+		})
+
+		var vol *ec2.Volume
+		if err != nil {
+			aerr, ok := err.(awserr.Error)
+			if !ok || aerr.Code() != "InvalidVolume.NotFound" {
+				return err
+			}
+		} else if len(result.Volumes) == 1 {
+			vol = result.Volumes[0]
+		}
+
+		done, err := check(vol)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("volume %s did not reach the desired state within %s", volID, volumePollMaxWait)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func waitForVolumeState(ctx context.Context, client EC2API, volID, state string) error {
+	return pollVolume(ctx, client, volID, func(vol *ec2.Volume) (bool, error) {
+		return vol != nil && *vol.State == state, nil
+	})
+}
+
+func waitForVolumeDeleted(ctx context.Context, client EC2API, volID string) error {
+	return pollVolume(ctx, client, volID, func(vol *ec2.Volume) (bool, error) {
+		return vol == nil || *vol.State == ec2.VolumeStateDeleted, nil
+	})
+}
+
+func waitForAttached(ctx context.Context, client EC2API, volID string) error {
+	return pollVolume(ctx, client, volID, func(vol *ec2.Volume) (bool, error) {
+		if vol == nil {
+			return false, fmt.Errorf("volume %s disappeared while waiting for it to attach", volID)
+		}
+		return *vol.State == ec2.VolumeStateInUse &&
+			len(vol.Attachments) > 0 && *vol.Attachments[0].State == ec2.VolumeAttachmentStateAttached, nil
+	})
+}
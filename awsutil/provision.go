@@ -0,0 +1,163 @@
+package awsutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//CreateOpts carries the parameters goat needs to call ec2.CreateVolume for a
+//volume that was described by tags but not found during discovery
+type CreateOpts struct {
+	SizeGiB    int64
+	VolumeType string
+	Iops       int64
+	Throughput int64
+	KmsKeyID   string
+	OutpostArn string
+}
+
+func createOpts(v *EbsVol) *CreateOpts {
+	if v.CreateOpts == nil {
+		v.CreateOpts = &CreateOpts{}
+	}
+	return v.CreateOpts
+}
+
+//ProvisionMissingVolumes creates the volumes a tagged group is missing, using
+//the CreateOpts parsed off an already-discovered sibling volume as the
+//template. It blocks until every created volume reaches the "available"
+//state so callers can safely hand the result back into the discovery map
+func (e *EC2Instance) ProvisionMissingVolumes(volName string, existing []EbsVol, missing int) ([]EbsVol, error) {
+	volGroupLogger := log.WithFields(log.Fields{"vol_name": volName})
+
+	if len(existing) == 0 {
+		return nil, fmt.Errorf("cannot provision missing volumes for %q: no existing volume to use as a template", volName)
+	}
+
+	template := existing[0].CreateOpts
+	if template == nil {
+		return nil, fmt.Errorf("cannot provision missing volumes for %q: GOAT-IN:SizeGiB/VolumeType tags not found on existing members", volName)
+	}
+	if template.SizeGiB <= 0 {
+		return nil, fmt.Errorf("cannot provision missing volumes for %q: GOAT-IN:SizeGiB tag is missing or invalid", volName)
+	}
+	if err := validateVolumeType(template); err != nil {
+		return nil, fmt.Errorf("cannot provision missing volumes for %q: %v", volName, err)
+	}
+
+	created := make([]EbsVol, 0, missing)
+	for i := 0; i < missing; i++ {
+		index := len(existing) + i
+		volGroupLogger.Infof("Provisioning missing volume %d/%d", i+1, missing)
+
+		vol, err := e.createVolume(volName, existing[0], index)
+		if err != nil {
+			return created, fmt.Errorf("creating volume %d/%d for %q: %v", i+1, missing, volName, err)
+		}
+
+		if e.Artifact == nil {
+			e.Artifact = NewArtifact(e.Region)
+		}
+		e.Artifact.Add(volName, EbsVolID(vol.EbsVolID))
+
+		if err := waitForVolumeState(context.Background(), e.EC2Client, vol.EbsVolID, ec2.VolumeStateAvailable); err != nil {
+			return created, fmt.Errorf("waiting for volume %s to become available: %v", vol.EbsVolID, err)
+		}
+
+		created = append(created, vol)
+	}
+
+	return created, nil
+}
+
+func (e *EC2Instance) createVolume(volName string, template EbsVol, index int) (EbsVol, error) {
+	opts := template.CreateOpts
+
+	clientToken := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s%s%s%d", e.Prefix, e.NodeID, volName, index))))
+
+	inherited, err := e.InheritedTags()
+	if err != nil {
+		return EbsVol{}, fmt.Errorf("reading inheritable instance tags: %v", err)
+	}
+
+	params := &ec2.CreateVolumeInput{
+		AvailabilityZone:  aws.String(e.Az),
+		Size:              aws.Int64(opts.SizeGiB),
+		VolumeType:        aws.String(opts.VolumeType),
+		ClientToken:       aws.String(clientToken),
+		TagSpecifications: []*ec2.TagSpecification{volumeTagSpec(e.Prefix, e.NodeID, volName, template, inherited)},
+	}
+
+	if opts.Iops > 0 {
+		params.Iops = aws.Int64(opts.Iops)
+	}
+	if opts.Throughput > 0 {
+		params.Throughput = aws.Int64(opts.Throughput)
+	}
+	if opts.KmsKeyID != "" {
+		params.KmsKeyId = aws.String(opts.KmsKeyID)
+		params.Encrypted = aws.Bool(true)
+	}
+	if opts.OutpostArn != "" {
+		params.OutpostArn = aws.String(opts.OutpostArn)
+	}
+
+	result, err := e.EC2Client.CreateVolume(params)
+	if err != nil {
+		return EbsVol{}, err
+	}
+
+	return EbsVol{
+		EbsVolID:   *result.VolumeId,
+		VolumeName: volName,
+		RaidLevel:  template.RaidLevel,
+		VolumeSize: template.VolumeSize,
+		MountPath:  template.MountPath,
+		FsType:     template.FsType,
+		CreateOpts: opts,
+	}, nil
+}
+
+func volumeTagSpec(prefix, nodeID, volName string, template EbsVol, inherited map[string]string) *ec2.TagSpecification {
+	tags := []*ec2.Tag{
+		{Key: aws.String("GOAT-IN:Prefix"), Value: aws.String(prefix)},
+		{Key: aws.String("GOAT-IN:NodeId"), Value: aws.String(nodeID)},
+		{Key: aws.String("GOAT-IN:VolumeName"), Value: aws.String(volName)},
+		{Key: aws.String("GOAT-IN:RaidLevel"), Value: aws.String(fmt.Sprintf("%d", template.RaidLevel))},
+		{Key: aws.String("GOAT-IN:VolumeSize"), Value: aws.String(fmt.Sprintf("%d", template.VolumeSize))},
+		{Key: aws.String("GOAT-IN:MountPath"), Value: aws.String(template.MountPath)},
+		{Key: aws.String("GOAT-IN:FsType"), Value: aws.String(template.FsType)},
+	}
+
+	for key, value := range inherited {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	if opts := template.CreateOpts; opts != nil {
+		tags = append(tags, &ec2.Tag{Key: aws.String("GOAT-IN:SizeGiB"), Value: aws.String(fmt.Sprintf("%d", opts.SizeGiB))})
+		tags = append(tags, &ec2.Tag{Key: aws.String("GOAT-IN:VolumeType"), Value: aws.String(opts.VolumeType)})
+		if opts.Iops > 0 {
+			tags = append(tags, &ec2.Tag{Key: aws.String("GOAT-IN:Iops"), Value: aws.String(fmt.Sprintf("%d", opts.Iops))})
+		}
+		if opts.Throughput > 0 {
+			tags = append(tags, &ec2.Tag{Key: aws.String("GOAT-IN:Throughput"), Value: aws.String(fmt.Sprintf("%d", opts.Throughput))})
+		}
+		if opts.KmsKeyID != "" {
+			tags = append(tags, &ec2.Tag{Key: aws.String("GOAT-IN:KmsKeyID"), Value: aws.String(opts.KmsKeyID)})
+		}
+		if opts.OutpostArn != "" {
+			tags = append(tags, &ec2.Tag{Key: aws.String("GOAT-IN:OutpostArn"), Value: aws.String(opts.OutpostArn)})
+		}
+	}
+
+	return &ec2.TagSpecification{
+		ResourceType: aws.String(ec2.ResourceTypeVolume),
+		Tags:         tags,
+	}
+}
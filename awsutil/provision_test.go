@@ -0,0 +1,85 @@
+package awsutil
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+
+	"github.com/alexballas/goat/awsutil/mocks"
+)
+
+func TestProvisionMissingVolumesNoTemplate(t *testing.T) {
+	e := &EC2Instance{Prefix: "goat", NodeID: "node1", Az: "eu-west-1a"}
+	existing := []EbsVol{{EbsVolID: "vol-1", VolumeName: "data"}}
+
+	if _, err := e.ProvisionMissingVolumes("data", existing, 1); err == nil {
+		t.Fatal("expected an error when no CreateOpts template is available")
+	}
+}
+
+func TestProvisionMissingVolumesInvalidType(t *testing.T) {
+	e := &EC2Instance{Prefix: "goat", NodeID: "node1", Az: "eu-west-1a"}
+	existing := []EbsVol{{
+		EbsVolID: "vol-1", VolumeName: "data",
+		CreateOpts: &CreateOpts{SizeGiB: 100, VolumeType: VolumeTypeIo1},
+	}}
+
+	if _, err := e.ProvisionMissingVolumes("data", existing, 1); err == nil {
+		t.Fatal("expected an error for io1 without Iops")
+	}
+}
+
+func TestProvisionMissingVolumesCreatesAndWaits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{EC2Client: client, Prefix: "goat", NodeID: "node1", Az: "eu-west-1a"}
+
+	existing := []EbsVol{{
+		EbsVolID: "vol-1", VolumeName: "data", RaidLevel: 0, VolumeSize: 2, MountPath: "/data", FsType: "ext4",
+		CreateOpts: &CreateOpts{SizeGiB: 100, VolumeType: VolumeTypeGp3},
+	}}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil)
+	client.EXPECT().CreateVolume(gomock.Any()).Return(&ec2.CreateVolumeOutput{VolumeId: aws.String("vol-2")}, nil)
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{VolumeId: aws.String("vol-2"), State: aws.String(ec2.VolumeStateAvailable)}},
+	}, nil)
+
+	created, err := e.ProvisionMissingVolumes("data", existing, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != 1 || created[0].EbsVolID != "vol-2" {
+		t.Fatalf("unexpected created volumes: %+v", created)
+	}
+}
+
+func TestProvisionMissingVolumesRecordsArtifactBeforeWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{EC2Client: client, Prefix: "goat", NodeID: "node1", Az: "eu-west-1a"}
+
+	existing := []EbsVol{{
+		EbsVolID: "vol-1", VolumeName: "data", RaidLevel: 0, VolumeSize: 2, MountPath: "/data", FsType: "ext4",
+		CreateOpts: &CreateOpts{SizeGiB: 100, VolumeType: VolumeTypeGp3},
+	}}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil)
+	client.EXPECT().CreateVolume(gomock.Any()).Return(&ec2.CreateVolumeOutput{VolumeId: aws.String("vol-2")}, nil)
+	client.EXPECT().DescribeVolumes(gomock.Any()).Return(nil, awserr.New("InternalError", "boom", nil))
+
+	if _, err := e.ProvisionMissingVolumes("data", existing, 1); err == nil {
+		t.Fatal("expected an error when waitForVolumeState fails")
+	}
+
+	if e.Artifact == nil || len(e.Artifact.Volumes["data"]) != 1 || e.Artifact.Volumes["data"][0] != "vol-2" {
+		t.Fatalf("expected vol-2 to be recorded in the artifact even though the wait failed, got: %+v", e.Artifact)
+	}
+}
@@ -0,0 +1,101 @@
+package awsutil
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const (
+	tagInheritEnable  = "GOAT-IN:InheritTags"
+	tagInheritKeys    = "GOAT-IN:InheritTagKeys"
+	tagReservedPrefix = "GOAT-IN:"
+)
+
+func (e *EC2Instance) instanceTags() (map[string]string, error) {
+	result, err := e.EC2Client.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(e.InstanceID)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			for _, tag := range instance.Tags {
+				tags[*tag.Key] = *tag.Value
+			}
+		}
+	}
+	return tags, nil
+}
+
+//InheritedTags returns the subset of this instance's own tags that should be
+//copied onto its EBS volumes, as driven by GOAT-IN:InheritTags=true or the
+//GOAT-IN:InheritTagKeys allowlist. Reserved GOAT-IN:* keys are never included
+func (e *EC2Instance) InheritedTags() (map[string]string, error) {
+	instanceTags, err := e.instanceTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	if allowlist, ok := instanceTags[tagInheritKeys]; ok && allowlist != "" {
+		for _, key := range strings.Split(allowlist, ",") {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	} else if instanceTags[tagInheritEnable] == "true" {
+		for key := range instanceTags {
+			keys = append(keys, key)
+		}
+	}
+
+	inherited := map[string]string{}
+	for _, key := range keys {
+		if strings.HasPrefix(key, tagReservedPrefix) {
+			continue
+		}
+		if value, ok := instanceTags[key]; ok {
+			inherited[key] = value
+		}
+	}
+	return inherited, nil
+}
+
+//SyncTags reconciles the instance's inherited tags onto every already
+//discovered volume in e.Vols, so operational tags like cost allocation reach
+//disks that were provisioned or attached before GOAT-IN:InheritTags was set
+func (e *EC2Instance) SyncTags() error {
+	inherited, err := e.InheritedTags()
+	if err != nil {
+		return fmt.Errorf("reading instance tags: %v", err)
+	}
+	if len(inherited) == 0 {
+		return nil
+	}
+
+	ec2Tags := make([]*ec2.Tag, 0, len(inherited))
+	for key, value := range inherited {
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	for volName, vols := range e.Vols {
+		for _, vol := range vols {
+			volLogger := log.WithFields(log.Fields{"vol_id": vol.EbsVolID, "vol_name": volName})
+
+			if _, err := e.EC2Client.CreateTags(&ec2.CreateTagsInput{
+				Resources: []*string{aws.String(vol.EbsVolID)},
+				Tags:      ec2Tags,
+			}); err != nil {
+				return fmt.Errorf("syncing tags onto volume %s (%s): %v", vol.EbsVolID, volName, err)
+			}
+			volLogger.Info("Synced inherited tags")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package awsutil
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+
+	"github.com/alexballas/goat/awsutil/mocks"
+)
+
+func instanceTagsOutput(tags map[string]string) *ec2.DescribeInstancesOutput {
+	ec2Tags := make([]*ec2.Tag, 0, len(tags))
+	for k, v := range tags {
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return &ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{Tags: ec2Tags}}}},
+	}
+}
+
+func TestInheritedTagsAllowlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{EC2Client: client, InstanceID: "i-123"}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(instanceTagsOutput(map[string]string{
+		"GOAT-IN:InheritTagKeys": "CostCenter, Env",
+		"CostCenter":             "eng",
+		"Env":                    "prod",
+		"Name":                   "my-instance",
+	}), nil)
+
+	got, err := e.InheritedTags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["CostCenter"] != "eng" || got["Env"] != "prod" {
+		t.Fatalf("unexpected inherited tags: %+v", got)
+	}
+	if _, ok := got["Name"]; ok {
+		t.Fatalf("Name should not be inherited without being in the allowlist: %+v", got)
+	}
+}
+
+func TestInheritedTagsAllNeverLeaksReservedKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{EC2Client: client, InstanceID: "i-123"}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(instanceTagsOutput(map[string]string{
+		"GOAT-IN:InheritTags": "true",
+		"GOAT-IN:Prefix":      "goat",
+		"CostCenter":          "eng",
+	}), nil)
+
+	got, err := e.InheritedTags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var keys []string
+	for k := range got {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) != 1 || keys[0] != "CostCenter" {
+		t.Fatalf("expected only CostCenter to be inherited, got %v", keys)
+	}
+}
+
+func TestSyncTagsNoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockEC2API(ctrl)
+	e := &EC2Instance{EC2Client: client, InstanceID: "i-123"}
+
+	client.EXPECT().DescribeInstances(gomock.Any()).Return(instanceTagsOutput(nil), nil)
+
+	if err := e.SyncTags(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
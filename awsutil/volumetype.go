@@ -0,0 +1,57 @@
+package awsutil
+
+import "fmt"
+
+//Supported values for the GOAT-IN:VolumeType tag, matching the EBS volume
+//type matrix plus the Snow-family types available on Snowball Edge devices
+const (
+	VolumeTypeGp2      = "gp2"
+	VolumeTypeGp3      = "gp3"
+	VolumeTypeIo1      = "io1"
+	VolumeTypeIo2      = "io2"
+	VolumeTypeSt1      = "st1"
+	VolumeTypeSc1      = "sc1"
+	VolumeTypeStandard = "standard"
+	VolumeTypeSbg1     = "sbg1"
+	VolumeTypeSbp1     = "sbp1"
+)
+
+func validateVolumeType(opts *CreateOpts) error {
+	if opts == nil {
+		return nil
+	}
+
+	switch opts.VolumeType {
+	case VolumeTypeGp2, VolumeTypeSt1, VolumeTypeSc1, VolumeTypeStandard, "":
+		if opts.Iops > 0 {
+			return fmt.Errorf("VolumeType %q does not support Iops", opts.VolumeType)
+		}
+		if opts.Throughput > 0 {
+			return fmt.Errorf("VolumeType %q does not support Throughput", opts.VolumeType)
+		}
+	case VolumeTypeGp3:
+		//Iops and Throughput are both optional on gp3
+	case VolumeTypeIo1, VolumeTypeIo2:
+		if opts.Iops <= 0 {
+			return fmt.Errorf("VolumeType %q requires Iops", opts.VolumeType)
+		}
+		if opts.Throughput > 0 {
+			return fmt.Errorf("VolumeType %q does not support Throughput", opts.VolumeType)
+		}
+	case VolumeTypeSbg1, VolumeTypeSbp1:
+		if opts.Iops > 0 {
+			return fmt.Errorf("VolumeType %q does not support Iops", opts.VolumeType)
+		}
+	default:
+		return fmt.Errorf("unsupported VolumeType %q", opts.VolumeType)
+	}
+
+	return nil
+}
+
+func volumeType(v EbsVol) string {
+	if v.CreateOpts == nil {
+		return ""
+	}
+	return v.CreateOpts.VolumeType
+}
@@ -0,0 +1,35 @@
+package awsutil
+
+import "testing"
+
+func TestValidateVolumeType(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *CreateOpts
+		wantErr bool
+	}{
+		{name: "nil opts", opts: nil},
+		{name: "gp2 plain", opts: &CreateOpts{VolumeType: VolumeTypeGp2}},
+		{name: "gp2 with iops is rejected", opts: &CreateOpts{VolumeType: VolumeTypeGp2, Iops: 100}, wantErr: true},
+		{name: "gp3 with iops and throughput", opts: &CreateOpts{VolumeType: VolumeTypeGp3, Iops: 3000, Throughput: 125}},
+		{name: "gp3 plain", opts: &CreateOpts{VolumeType: VolumeTypeGp3}},
+		{name: "io1 requires iops", opts: &CreateOpts{VolumeType: VolumeTypeIo1}, wantErr: true},
+		{name: "io1 with iops", opts: &CreateOpts{VolumeType: VolumeTypeIo1, Iops: 100}},
+		{name: "io2 with throughput is rejected", opts: &CreateOpts{VolumeType: VolumeTypeIo2, Iops: 100, Throughput: 50}, wantErr: true},
+		{name: "sbg1 plain", opts: &CreateOpts{VolumeType: VolumeTypeSbg1}},
+		{name: "sbp1 with iops is rejected", opts: &CreateOpts{VolumeType: VolumeTypeSbp1, Iops: 100}, wantErr: true},
+		{name: "unsupported type", opts: &CreateOpts{VolumeType: "magnetic"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVolumeType(tt.opts)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}